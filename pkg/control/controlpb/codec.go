@@ -0,0 +1,31 @@
+package controlpb
+
+import (
+	"encoding/json"
+
+	"google.golang.org/grpc/encoding"
+)
+
+// jsonCodec implements encoding.Codec using plain JSON instead of the
+// protobuf wire format, so Control can be served over real gRPC without a
+// protoc-gen-go-generated proto.Message implementation. Registered under the
+// name "json"; dial with grpc.CallContentSubtype("json") and serve with
+// grpc.NewServer() (content-subtype negotiation picks it automatically on the
+// request path codec name used by the client).
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return "json"
+}
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
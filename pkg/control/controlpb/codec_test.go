@@ -0,0 +1,27 @@
+package controlpb
+
+import "testing"
+
+func TestJSONCodecRoundTrip(t *testing.T) {
+	want := &PauseRequest{InfoHash: "deadbeef"}
+
+	data, err := (jsonCodec{}).Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	got := &PauseRequest{}
+	if err := (jsonCodec{}).Unmarshal(data, got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if got.InfoHash != want.InfoHash {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestJSONCodecName(t *testing.T) {
+	if name := (jsonCodec{}).Name(); name != "json" {
+		t.Errorf("Name() = %q, want %q", name, "json")
+	}
+}
@@ -0,0 +1,82 @@
+// Package controlpb holds the Go types for proto/control.proto.
+//
+// The proto toolchain (protoc, protoc-gen-go, protoc-gen-go-grpc) isn't
+// available in this build environment, so these aren't the usual
+// protoc-generated .pb.go/_grpc.pb.go output — they're hand-written, wire it
+// over gRPC using the "json" codec (see codec.go) rather than the protobuf
+// wire format. Swap this package for real generated code, and the json codec
+// for the default protobuf one, once protoc-gen-go/protoc-gen-go-grpc can run
+// here; ControlServer/GrpcServer are written to that same shape so the switch
+// is a regeneration, not a rewrite.
+package controlpb
+
+type AddRequest struct {
+	Source   string   `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	WebSeeds []string `protobuf:"bytes,2,rep,name=web_seeds,json=webSeeds,proto3" json:"web_seeds,omitempty"`
+}
+
+type AddResponse struct {
+	InfoHash string `protobuf:"bytes,1,opt,name=info_hash,json=infoHash,proto3" json:"info_hash,omitempty"`
+}
+
+type DeleteRequest struct {
+	InfoHash string `protobuf:"bytes,1,opt,name=info_hash,json=infoHash,proto3" json:"info_hash,omitempty"`
+}
+
+type DeleteResponse struct{}
+
+type TorrentsRequest struct{}
+
+type TorrentInfo struct {
+	InfoHash       string `protobuf:"bytes,1,opt,name=info_hash,json=infoHash,proto3" json:"info_hash,omitempty"`
+	Name           string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Length         int64  `protobuf:"varint,3,opt,name=length,proto3" json:"length,omitempty"`
+	BytesCompleted int64  `protobuf:"varint,4,opt,name=bytes_completed,json=bytesCompleted,proto3" json:"bytes_completed,omitempty"`
+	Peers          int32  `protobuf:"varint,5,opt,name=peers,proto3" json:"peers,omitempty"`
+}
+
+type TorrentsResponse struct {
+	Torrents []*TorrentInfo `protobuf:"bytes,1,rep,name=torrents,proto3" json:"torrents,omitempty"`
+}
+
+type StatsRequest struct {
+	InfoHash string `protobuf:"bytes,1,opt,name=info_hash,json=infoHash,proto3" json:"info_hash,omitempty"`
+}
+
+type PieceStat struct {
+	Index    int32 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Complete bool  `protobuf:"varint,2,opt,name=complete,proto3" json:"complete,omitempty"`
+}
+
+type StatsResponse struct {
+	BytesCompleted int64        `protobuf:"varint,1,opt,name=bytes_completed,json=bytesCompleted,proto3" json:"bytes_completed,omitempty"`
+	BytesMissing   int64        `protobuf:"varint,2,opt,name=bytes_missing,json=bytesMissing,proto3" json:"bytes_missing,omitempty"`
+	Peers          int32        `protobuf:"varint,3,opt,name=peers,proto3" json:"peers,omitempty"`
+	Pieces         []*PieceStat `protobuf:"bytes,4,rep,name=pieces,proto3" json:"pieces,omitempty"`
+}
+
+type VerifyRequest struct {
+	InfoHash string `protobuf:"bytes,1,opt,name=info_hash,json=infoHash,proto3" json:"info_hash,omitempty"`
+}
+
+type VerifyResponse struct{}
+
+type SetFilePriorityRequest struct {
+	InfoHash string `protobuf:"bytes,1,opt,name=info_hash,json=infoHash,proto3" json:"info_hash,omitempty"`
+	Path     string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Priority string `protobuf:"bytes,3,opt,name=priority,proto3" json:"priority,omitempty"`
+}
+
+type SetFilePriorityResponse struct{}
+
+type PauseRequest struct {
+	InfoHash string `protobuf:"bytes,1,opt,name=info_hash,json=infoHash,proto3" json:"info_hash,omitempty"`
+}
+
+type PauseResponse struct{}
+
+type ResumeRequest struct {
+	InfoHash string `protobuf:"bytes,1,opt,name=info_hash,json=infoHash,proto3" json:"info_hash,omitempty"`
+}
+
+type ResumeResponse struct{}
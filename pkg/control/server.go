@@ -0,0 +1,212 @@
+// Package control exposes a running libtorrent.Client over gRPC (and a
+// JSON-over-HTTP gateway for it), so external UIs and scripts can drive toru
+// without embedding the Go API.
+package control
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+
+	"github.com/anacrolix/torrent"
+	"google.golang.org/grpc"
+
+	"github.com/EnergoStalin/toru/pkg/control/controlpb"
+	"github.com/EnergoStalin/toru/pkg/libtorrent"
+)
+
+// GrpcServer implements controlpb.ControlServer on top of a *libtorrent.Client.
+type GrpcServer struct {
+	controlpb.UnimplementedControlServer
+	Client *libtorrent.Client
+}
+
+// NewGrpcServer wraps c for use as a controlpb.ControlServer and HTTP gateway.
+func NewGrpcServer(c *libtorrent.Client) *GrpcServer {
+	return &GrpcServer{Client: c}
+}
+
+func (s *GrpcServer) Add(ctx context.Context, req *controlpb.AddRequest) (*controlpb.AddResponse, error) {
+	t, err := s.Client.AddTorrentWithWebSeeds(req.Source, req.WebSeeds)
+	if err != nil {
+		return nil, err
+	}
+	return &controlpb.AddResponse{InfoHash: t.InfoHash().String()}, nil
+}
+
+func (s *GrpcServer) Delete(ctx context.Context, req *controlpb.DeleteRequest) (*controlpb.DeleteResponse, error) {
+	t, err := s.Client.FindByInfoHhash(req.InfoHash)
+	if err != nil {
+		return nil, err
+	}
+	s.Client.DropTorrent(t)
+	return &controlpb.DeleteResponse{}, nil
+}
+
+func (s *GrpcServer) Torrents(ctx context.Context, req *controlpb.TorrentsRequest) (*controlpb.TorrentsResponse, error) {
+	resp := &controlpb.TorrentsResponse{}
+	for _, t := range s.Client.ShowTorrents() {
+		resp.Torrents = append(resp.Torrents, &controlpb.TorrentInfo{
+			InfoHash:       t.InfoHash().String(),
+			Name:           t.Name(),
+			Length:         t.Length(),
+			BytesCompleted: t.BytesCompleted(),
+			Peers:          int32(len(t.PeerConns())),
+		})
+	}
+	return resp, nil
+}
+
+func (s *GrpcServer) Stats(ctx context.Context, req *controlpb.StatsRequest) (*controlpb.StatsResponse, error) {
+	t, err := s.Client.FindByInfoHhash(req.InfoHash)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &controlpb.StatsResponse{
+		BytesCompleted: t.BytesCompleted(),
+		BytesMissing:   t.BytesMissing(),
+		Peers:          int32(len(t.PeerConns())),
+	}
+	for i := 0; i < t.NumPieces(); i++ {
+		resp.Pieces = append(resp.Pieces, &controlpb.PieceStat{
+			Index:    int32(i),
+			Complete: t.PieceState(i).Complete,
+		})
+	}
+	return resp, nil
+}
+
+func (s *GrpcServer) Verify(ctx context.Context, req *controlpb.VerifyRequest) (*controlpb.VerifyResponse, error) {
+	t, err := s.Client.FindByInfoHhash(req.InfoHash)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.VerifyData(); err != nil {
+		return nil, err
+	}
+	return &controlpb.VerifyResponse{}, nil
+}
+
+func (s *GrpcServer) Pause(ctx context.Context, req *controlpb.PauseRequest) (*controlpb.PauseResponse, error) {
+	t, err := s.Client.FindByInfoHhash(req.InfoHash)
+	if err != nil {
+		return nil, err
+	}
+	t.DisallowDataDownload()
+	return &controlpb.PauseResponse{}, nil
+}
+
+func (s *GrpcServer) Resume(ctx context.Context, req *controlpb.ResumeRequest) (*controlpb.ResumeResponse, error) {
+	t, err := s.Client.FindByInfoHhash(req.InfoHash)
+	if err != nil {
+		return nil, err
+	}
+	t.AllowDataDownload()
+	return &controlpb.ResumeResponse{}, nil
+}
+
+func (s *GrpcServer) SetFilePriority(ctx context.Context, req *controlpb.SetFilePriorityRequest) (*controlpb.SetFilePriorityResponse, error) {
+	t, err := s.Client.FindByInfoHhash(req.InfoHash)
+	if err != nil {
+		return nil, err
+	}
+
+	var f *torrent.File
+	for _, candidate := range t.Files() {
+		if candidate.Path() == req.Path {
+			f = candidate
+			break
+		}
+	}
+	if f == nil {
+		return nil, fmt.Errorf("control: no file at path: %v", req.Path)
+	}
+
+	priority, err := parsePriority(req.Priority)
+	if err != nil {
+		return nil, err
+	}
+	f.SetPriority(priority)
+	return &controlpb.SetFilePriorityResponse{}, nil
+}
+
+func parsePriority(s string) (torrent.PiecePriority, error) {
+	switch s {
+	case "now":
+		return torrent.PiecePriorityNow, nil
+	case "readahead":
+		return torrent.PiecePriorityReadahead, nil
+	case "normal":
+		return torrent.PiecePriorityNormal, nil
+	case "none", "":
+		return torrent.PiecePriorityNone, nil
+	default:
+		return 0, fmt.Errorf("control: unknown priority: %v", s)
+	}
+}
+
+// HTTPGateway translates JSON-over-HTTP requests to GrpcServer calls, one path
+// per RPC, e.g. POST /control/Add with a JSON-encoded AddRequest body.
+func (s *GrpcServer) HTTPGateway() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/control/Add", jsonHandler(s.Add))
+	mux.HandleFunc("/control/Delete", jsonHandler(s.Delete))
+	mux.HandleFunc("/control/Torrents", jsonHandler(s.Torrents))
+	mux.HandleFunc("/control/Stats", jsonHandler(s.Stats))
+	mux.HandleFunc("/control/Verify", jsonHandler(s.Verify))
+	mux.HandleFunc("/control/SetFilePriority", jsonHandler(s.SetFilePriority))
+	mux.HandleFunc("/control/Pause", jsonHandler(s.Pause))
+	mux.HandleFunc("/control/Resume", jsonHandler(s.Resume))
+	return mux
+}
+
+// StartControlServer registers s as a controlpb.ControlServer and serves it
+// over gRPC on addr, a separate port from the streaming HTTP server so
+// control access can be firewalled off independently. The returned
+// *grpc.Server can be stopped with GracefulStop.
+func (s *GrpcServer) StartControlServer(addr string) (*grpc.Server, error) {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("control: couldn't listen on %v: %v", addr, err)
+	}
+
+	grpcServer := grpc.NewServer()
+	controlpb.RegisterControlServer(grpcServer, s)
+
+	go func() {
+		if err := grpcServer.Serve(lis); err != nil && err != grpc.ErrServerStopped {
+			log.Println(err)
+		}
+	}()
+
+	return grpcServer, nil
+}
+
+// jsonHandler adapts a (ctx, *Req) (*Resp, error) RPC method into a plain HTTP
+// handler that decodes the request body as JSON and encodes the response as JSON.
+func jsonHandler[Req any, Resp any](rpc func(context.Context, *Req) (*Resp, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := new(Req)
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		resp, err := rpc(r.Context(), req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
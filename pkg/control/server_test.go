@@ -0,0 +1,147 @@
+package control
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/anacrolix/torrent"
+	"github.com/anacrolix/torrent/bencode"
+	"github.com/anacrolix/torrent/metainfo"
+	"github.com/anacrolix/torrent/storage"
+
+	"github.com/EnergoStalin/toru/pkg/control/controlpb"
+	"github.com/EnergoStalin/toru/pkg/libtorrent"
+)
+
+// newTestServer builds a GrpcServer wrapping a libtorrent.Client with one
+// torrent already added (via a real .torrent file on disk, so GotInfo
+// resolves immediately without network), and returns it along with the
+// torrent's hex info hash, the same form Add/Torrents hand callers.
+func newTestServer(t *testing.T) (*GrpcServer, string) {
+	t.Helper()
+
+	cfg := torrent.NewDefaultClientConfig()
+	cfg.DisableTCP = true
+	cfg.DisableUTP = true
+	cfg.NoDHT = true
+	cfg.ListenPort = 0
+	cfg.Seed = false
+
+	dir := t.TempDir()
+	cfg.DefaultStorage = storage.NewFile(dir)
+	cl, err := torrent.NewClient(cfg)
+	if err != nil {
+		t.Fatalf("torrent.NewClient: %v", err)
+	}
+	t.Cleanup(func() { cl.Close() })
+
+	info := metainfo.Info{
+		PieceLength: 1 << 18,
+		Name:        "video.mkv",
+		Length:      1024,
+		Pieces:      make([]byte, metainfo.HashSize),
+	}
+	infoBytes, err := bencode.Marshal(info)
+	if err != nil {
+		t.Fatalf("bencode.Marshal(info): %v", err)
+	}
+
+	mi := &metainfo.MetaInfo{InfoBytes: infoBytes}
+	torrentPath := filepath.Join(dir, "test.torrent")
+	f, err := os.Create(torrentPath)
+	if err != nil {
+		t.Fatalf("os.Create: %v", err)
+	}
+	if err := mi.Write(f); err != nil {
+		t.Fatalf("mi.Write: %v", err)
+	}
+	f.Close()
+
+	c := &libtorrent.Client{TorrentClient: cl}
+	tt, err := c.AddTorrentWithWebSeeds(torrentPath, nil)
+	if err != nil {
+		t.Fatalf("AddTorrentWithWebSeeds: %v", err)
+	}
+
+	return NewGrpcServer(c), tt.InfoHash().String()
+}
+
+func TestTorrentsReturnsAddableHash(t *testing.T) {
+	s, hash := newTestServer(t)
+
+	resp, err := s.Torrents(context.Background(), &controlpb.TorrentsRequest{})
+	if err != nil {
+		t.Fatalf("Torrents: %v", err)
+	}
+	if len(resp.Torrents) != 1 || resp.Torrents[0].InfoHash != hash {
+		t.Fatalf("Torrents() = %+v, want one entry with InfoHash %q", resp.Torrents, hash)
+	}
+}
+
+func TestByHashRPCsResolveTheHashTorrentsReturns(t *testing.T) {
+	s, hash := newTestServer(t)
+
+	if _, err := s.Stats(context.Background(), &controlpb.StatsRequest{InfoHash: hash}); err != nil {
+		t.Errorf("Stats(%q): %v", hash, err)
+	}
+	if _, err := s.Pause(context.Background(), &controlpb.PauseRequest{InfoHash: hash}); err != nil {
+		t.Errorf("Pause(%q): %v", hash, err)
+	}
+	if _, err := s.Resume(context.Background(), &controlpb.ResumeRequest{InfoHash: hash}); err != nil {
+		t.Errorf("Resume(%q): %v", hash, err)
+	}
+	if _, err := s.SetFilePriority(context.Background(), &controlpb.SetFilePriorityRequest{InfoHash: hash, Path: "video.mkv", Priority: "now"}); err != nil {
+		t.Errorf("SetFilePriority(%q): %v", hash, err)
+	}
+	if _, err := s.Delete(context.Background(), &controlpb.DeleteRequest{InfoHash: hash}); err != nil {
+		t.Errorf("Delete(%q): %v", hash, err)
+	}
+}
+
+func TestByHashRPCsRejectUnknownHash(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	if _, err := s.Stats(context.Background(), &controlpb.StatsRequest{InfoHash: "deadbeef"}); err == nil {
+		t.Error("Stats with unknown hash: want error, got nil")
+	}
+	if _, err := s.Pause(context.Background(), &controlpb.PauseRequest{InfoHash: "deadbeef"}); err == nil {
+		t.Error("Pause with unknown hash: want error, got nil")
+	}
+}
+
+func TestHTTPGatewayPauseUnknownHash(t *testing.T) {
+	s, _ := newTestServer(t)
+	srv := httptest.NewServer(s.HTTPGateway())
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/control/Pause", "application/json", strings.NewReader(`{"info_hash":"deadbeef"}`))
+	if err != nil {
+		t.Fatalf("POST /control/Pause: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusInternalServerError)
+	}
+}
+
+func TestHTTPGatewayMalformedBody(t *testing.T) {
+	s, _ := newTestServer(t)
+	srv := httptest.NewServer(s.HTTPGateway())
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/control/Pause", "application/json", strings.NewReader(`{not json`))
+	if err != nil {
+		t.Fatalf("POST /control/Pause: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+}
@@ -10,11 +10,11 @@ import (
 	"os"
 	"path"
 	"slices"
-	"strconv"
 	"strings"
-	"time"
+	"sync"
 
 	"github.com/anacrolix/torrent"
+	"github.com/anacrolix/torrent/metainfo"
 	"github.com/anacrolix/torrent/storage"
 )
 
@@ -37,6 +37,54 @@ type Client struct {
 	Torrents []*torrent.Torrent
 	// Disable IPV6
 	DisableIPV6 bool
+	// HTTP/S web seeds (BEP-19) added to every torrent on add, on top of any
+	// url-list/httpseeds already present in the torrent's metainfo. Useful for
+	// bootstrapping streaming from a mirror when swarm peers are scarce.
+	WebSeeds []string
+	// ReadaheadBytes is the byte range ahead of the read head that readers keep at
+	// elevated priority while streaming. Leave at 0 to use the library default.
+	ReadaheadBytes int64
+	// HeadTailBoostPct is the fraction (0-1) of a file's start and end bumped to
+	// torrent.PiecePriorityNow so container headers/indexes (mp4 moov, mkv cues)
+	// resolve quickly when a player seeks.
+	HeadTailBoostPct float64
+	// Backend selects which StorageBackend cfg.DefaultStorage uses. Defaults to
+	// StorageFile.
+	Backend StorageBackend
+	// StorageOpener, if set, is used instead of Backend to construct a storage
+	// implementation, an escape hatch for callers wanting a custom backend.
+	StorageOpener func(*metainfo.Info, metainfo.Hash) storage.ClientImplCloser
+	// CacheBytes caps the memory used by StorageMemory; completed pieces behind the
+	// read head are evicted once exceeded. Ignored by other backends.
+	CacheBytes int64
+	// SessionDir, if set, is where SaveSession/RestoreSession persist the manifest
+	// of added torrents. Defaults to DataDir when empty.
+	SessionDir string
+	// RestoreOnInit, when true, calls RestoreSession at the end of Init so torrents
+	// saved in a previous run are re-added automatically.
+	RestoreOnInit bool
+	// selectedFilesMu guards selectedFiles.
+	selectedFilesMu sync.Mutex
+	// selectedFiles tracks the last 1-based episode index served per info hash, so
+	// SaveSession can persist it for RestoreSession to reapply.
+	selectedFiles map[string]int
+}
+
+// trackSelectedFile records ep as the last episode served for hash.
+func (c *Client) trackSelectedFile(hash string, ep int) {
+	c.selectedFilesMu.Lock()
+	defer c.selectedFilesMu.Unlock()
+	if c.selectedFiles == nil {
+		c.selectedFiles = make(map[string]int)
+	}
+	c.selectedFiles[hash] = ep
+}
+
+// selectedFile returns the last episode served for hash, or 0 if none.
+func (c *Client) selectedFile(hash string) int {
+	c.selectedFilesMu.Lock()
+	defer c.selectedFilesMu.Unlock()
+	return c.selectedFiles[hash]
 }
 
 // create a default client, must call Init afterwords
@@ -65,7 +113,12 @@ func (c *Client) Init() error {
 
 	cfg.ListenPort = c.TorrentPort
 	c.DataDir = s
-	cfg.DefaultStorage = storage.NewFileByInfoHash(c.DataDir)
+
+	storageImpl, err := c.getStorageImpl()
+	if err != nil {
+		return err
+	}
+	cfg.DefaultStorage = storageImpl
 
 	client, err := torrent.NewClient(cfg)
 	if err != nil {
@@ -74,6 +127,13 @@ func (c *Client) Init() error {
 
 	c.StartServer()
 	c.TorrentClient = client
+
+	if c.RestoreOnInit {
+		if err := c.RestoreSession(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -107,7 +167,12 @@ func getSortedFilesList(t *torrent.Torrent) []*torrent.File {
 }
 
 func GetVideoFile(t *torrent.Torrent, episode int) (*torrent.File, error) {
-	f := getSortedFilesList(t)[episode-1]
+	files := getSortedFilesList(t)
+	if episode < 1 || episode > len(files) {
+		return nil, fmt.Errorf("server handler: episode %d out of range [1, %d]", episode, len(files))
+	}
+
+	f := files[episode-1]
 	ext := path.Ext(f.Path())
 	switch ext {
 	case ".mp4", ".mkv", ".avi", ".avif", ".av1", ".mov", ".flv", ".f4v", ".webm", ".wmv", ".mpeg", ".mpg", ".mlv", ".hevc", ".flac", ".flic":
@@ -117,43 +182,33 @@ func GetVideoFile(t *torrent.Torrent, episode int) (*torrent.File, error) {
 	}
 }
 
-// handler for ServeTorrent
-func (c *Client) handler(w http.ResponseWriter, r *http.Request) {
-	ts := c.TorrentClient.Torrents()
-	queries := r.URL.Query()
-	// get hash of torrent
-	hash := queries.Get("hash")
-	// get episode
-	ep, err := strconv.Atoi(queries.Get("ep"))
-	if err != nil {
-		http.Error(w, http.StatusText(400), http.StatusBadRequest)
+// boostHeadTail raises (or resets) the priority of the pieces covering the first and
+// last HeadTailBoostPct of f, so that container headers/indexes resolve quickly when
+// seeking. A no-op when HeadTailBoostPct isn't configured.
+func (c *Client) boostHeadTail(t *torrent.Torrent, f *torrent.File, priority torrent.PiecePriority) {
+	pct := c.HeadTailBoostPct
+	if pct <= 0 {
 		return
 	}
 
-	// idk why but this is always mangled af
-	hash = strings.TrimSpace(hash)
-	hash = strings.ReplaceAll(hash, "\n", "")
-
-	if hash == "" {
-		log.Println("server handler: Hash query is empty")
+	pieceLength := t.Info().PieceLength
+	if pieceLength == 0 {
 		return
 	}
 
-	for _, ff := range ts {
-		<-ff.GotInfo()
-		ih := ff.InfoHash().String()
-		
-		if ih == hash {
-			f, err := GetVideoFile(ff, ep)
-			if err != nil {
-				log.Println(err)
-				return
-			}
+	boost := int64(float64(f.Length()) * pct)
+	if boost <= 0 {
+		return
+	}
 
-			w.Header().Set("Content-Type", "video/mp4")
-			http.ServeContent(w, r, f.DisplayPath(), time.Unix(f.Torrent().Metainfo().CreationDate, 0), f.NewReader())
+	setRange := func(start, end int64) {
+		for i := start / pieceLength; i <= end/pieceLength; i++ {
+			t.Piece(int(i)).SetPriority(priority)
 		}
 	}
+
+	setRange(f.Offset(), f.Offset()+boost-1)
+	setRange(f.Offset()+f.Length()-boost, f.Offset()+f.Length()-1)
 }
 
 // start the server in the background
@@ -161,7 +216,9 @@ func (c *Client) StartServer() {
 	// :8080 for localhost:8080/
 	port := fmt.Sprintf(":%s", c.Port)
 	c.srv = &http.Server{Addr: port}
-	http.HandleFunc("/stream", c.handler)
+	http.HandleFunc("/stream", c.StreamHandler)
+	http.HandleFunc("/files", c.FilesHandler)
+	http.HandleFunc("/subs", c.SubsHandler)
 
 	go func() {
 		if err := c.srv.ListenAndServe(); err != nil {
@@ -203,6 +260,7 @@ func (c *Client) AddMagnet(magnet string) (*torrent.Torrent, error) {
 		return nil, err
 	}
 	<-t.GotInfo()
+	c.addWebSeeds(t, nil)
 	return t, nil
 }
 
@@ -212,9 +270,34 @@ func (c *Client) AddTorrentFile(file string) (*torrent.Torrent, error) {
 		return nil, err
 	}
 	<-t.GotInfo()
+	c.addWebSeeds(t, nil)
+	return t, nil
+}
+
+// AddTorrentWithWebSeeds behaves like AddTorrent but additionally augments the
+// torrent with HTTP/S web seeds (BEP-19/BEP-17) once metadata is available. Seeds
+// passed here are combined with any global c.WebSeeds. This is particularly useful
+// for the streaming handler, which otherwise blocks on swarm peers for both
+// GotInfo and piece availability.
+func (c *Client) AddTorrentWithWebSeeds(source string, seeds []string) (*torrent.Torrent, error) {
+	t, err := c.AddTorrent(source)
+	if err != nil {
+		return nil, err
+	}
+	t.AddWebSeeds(seeds)
 	return t, nil
 }
 
+// addWebSeeds applies the client-wide WebSeeds, if any, to a torrent that has
+// already got its info.
+func (c *Client) addWebSeeds(t *torrent.Torrent, seeds []string) {
+	all := append(slices.Clone(c.WebSeeds), seeds...)
+	if len(all) == 0 {
+		return
+	}
+	t.AddWebSeeds(all)
+}
+
 func (c *Client) AddTorrentURL(url string) (*torrent.Torrent, error) {
 	resp, err := http.Get(url)
 	if err != nil {
@@ -242,6 +325,7 @@ func (c *Client) AddTorrentURL(url string) (*torrent.Torrent, error) {
 		return nil, err
 	}
 	<-t.GotInfo()
+	c.addWebSeeds(t, nil)
 	return t, nil
 }
 
@@ -251,11 +335,12 @@ func (c *Client) Close() (errs []error) {
 }
 
 // look through the torrent files the client is handling and return a torrent with a
-// matching info hash
+// matching info hash. infoHash is the hex string form (t.InfoHash().String()),
+// matching what Add and Torrents hand back to callers.
 func (c *Client) FindByInfoHhash(infoHash string) (*torrent.Torrent, error) {
 	torrents := c.TorrentClient.Torrents()
 	for _, t := range torrents {
-		if t.InfoHash().AsString() == infoHash {
+		if t.InfoHash().String() == infoHash {
 			return t, nil
 		}
 	}
@@ -263,7 +348,12 @@ func (c *Client) FindByInfoHhash(infoHash string) (*torrent.Torrent, error) {
 }
 
 func (c *Client) DropTorrent(t *torrent.Torrent) {
+	hash := t.InfoHash().String()
 	t.Drop()
+
+	c.selectedFilesMu.Lock()
+	delete(c.selectedFiles, hash)
+	c.selectedFilesMu.Unlock()
 }
 
 // Create storage path if it doesn't exist and return Path
@@ -0,0 +1,54 @@
+package libtorrent
+
+import (
+	"testing"
+
+	"github.com/anacrolix/torrent"
+)
+
+func TestBoostHeadTailSetsHeadAndTailPieces(t *testing.T) {
+	// One piece per 100 bytes, a 1000 byte file: boosting 20% should touch
+	// pieces 0-1 (head) and 8-9 (tail), leaving the middle untouched.
+	c := &Client{HeadTailBoostPct: 0.2}
+	tt := newTestTorrentPieceLength(t, "show", map[string]int64{"video.mkv": 1000}, 100)
+	f := tt.Files()[0]
+
+	c.boostHeadTail(tt, f, torrent.PiecePriorityNow)
+
+	want := map[int]bool{0: true, 1: true, 8: true, 9: true}
+	for i := 0; i < tt.NumPieces(); i++ {
+		got := tt.Piece(i).State().Priority == torrent.PiecePriorityNow
+		if got != want[i] {
+			t.Errorf("piece %d boosted = %v, want %v", i, got, want[i])
+		}
+	}
+}
+
+func TestBoostHeadTailNoopWithoutPct(t *testing.T) {
+	c := &Client{}
+	tt := newTestTorrent(t, "show", map[string]int64{"video.mkv": 1000})
+	f := tt.Files()[0]
+
+	c.boostHeadTail(tt, f, torrent.PiecePriorityNow)
+
+	for i := 0; i < tt.NumPieces(); i++ {
+		if tt.Piece(i).State().Priority == torrent.PiecePriorityNow {
+			t.Errorf("piece %d boosted despite HeadTailBoostPct being unset", i)
+		}
+	}
+}
+
+func TestBoostHeadTailReset(t *testing.T) {
+	c := &Client{HeadTailBoostPct: 0.2}
+	tt := newTestTorrent(t, "show", map[string]int64{"video.mkv": 1000})
+	f := tt.Files()[0]
+
+	c.boostHeadTail(tt, f, torrent.PiecePriorityNow)
+	c.boostHeadTail(tt, f, torrent.PiecePriorityNone)
+
+	for i := 0; i < tt.NumPieces(); i++ {
+		if tt.Piece(i).State().Priority == torrent.PiecePriorityNow {
+			t.Errorf("piece %d still boosted after resetting to PiecePriorityNone", i)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package libtorrent
+
+import (
+	"testing"
+
+	"github.com/anacrolix/torrent"
+	"github.com/anacrolix/torrent/bencode"
+	"github.com/anacrolix/torrent/metainfo"
+)
+
+// newTestTorrent builds an in-memory *torrent.Torrent with the given files
+// (path -> length), with metadata available immediately and no network or
+// piece data required, for exercising file-selection logic in isolation.
+func newTestTorrent(t *testing.T, name string, files map[string]int64) *torrent.Torrent {
+	t.Helper()
+	return newTestTorrentPieceLength(t, name, files, 1<<18)
+}
+
+// newTestTorrentPieceLength is newTestTorrent with an explicit piece length,
+// for tests that need to assert on specific piece indices.
+func newTestTorrentPieceLength(t *testing.T, name string, files map[string]int64, pieceLength int64) *torrent.Torrent {
+	t.Helper()
+
+	cfg := torrent.NewDefaultClientConfig()
+	cfg.DisableTCP = true
+	cfg.DisableUTP = true
+	cfg.NoDHT = true
+	cfg.ListenPort = 0
+	cfg.Seed = false
+	cfg.DefaultStorage = newMemoryStorage(0)
+
+	cl, err := torrent.NewClient(cfg)
+	if err != nil {
+		t.Fatalf("torrent.NewClient: %v", err)
+	}
+	t.Cleanup(func() { cl.Close() })
+
+	info := metainfo.Info{
+		PieceLength: pieceLength,
+		Name:        name,
+	}
+	var total int64
+	for path, length := range files {
+		info.Files = append(info.Files, metainfo.FileInfo{Path: []string{path}, Length: length})
+		total += length
+	}
+	numPieces := int((total + info.PieceLength - 1) / info.PieceLength)
+	if numPieces == 0 {
+		numPieces = 1
+	}
+	info.Pieces = make([]byte, metainfo.HashSize*numPieces)
+
+	infoBytes, err := bencode.Marshal(info)
+	if err != nil {
+		t.Fatalf("bencode.Marshal(info): %v", err)
+	}
+
+	spec, err := torrent.TorrentSpecFromMetaInfoErr(&metainfo.MetaInfo{InfoBytes: infoBytes})
+	if err != nil {
+		t.Fatalf("TorrentSpecFromMetaInfoErr: %v", err)
+	}
+
+	tt, _, err := cl.AddTorrentSpec(spec)
+	if err != nil {
+		t.Fatalf("AddTorrentSpec: %v", err)
+	}
+	<-tt.GotInfo()
+	return tt
+}
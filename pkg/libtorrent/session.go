@@ -0,0 +1,123 @@
+package libtorrent
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/anacrolix/torrent"
+)
+
+// sessionEntry records enough about one added torrent to re-add it on restore.
+type sessionEntry struct {
+	// Source is a magnet URI, or the path of a cached .torrent file under
+	// SessionDir, re-added via AddMagnet/AddTorrentFile on restore.
+	Source string `json:"source"`
+	// Seed mirrors the client-wide seed flag at save time.
+	Seed bool `json:"seed"`
+	// SelectedFile is the 1-based sorted file index last served via /stream, if
+	// any, so RestoreSession can reapply its streaming priority.
+	SelectedFile int `json:"selected_file,omitempty"`
+}
+
+// sessionManifest is the JSON document persisted under SessionDir.
+type sessionManifest struct {
+	Torrents []sessionEntry `json:"torrents"`
+}
+
+// sessionDir returns where the session manifest and cached .torrent files live,
+// falling back to DataDir when SessionDir isn't set.
+func (c *Client) sessionDir() string {
+	if c.SessionDir != "" {
+		return c.SessionDir
+	}
+	return c.DataDir
+}
+
+func (c *Client) manifestPath() string {
+	return path.Join(c.sessionDir(), "session.json")
+}
+
+// SaveSession persists the set of currently added torrents to a JSON manifest
+// under SessionDir. Each torrent's .torrent file is cached alongside the
+// manifest so it can be re-added on restore without re-fetching metadata; the
+// underlying storage already has whatever piece data was downloaded, and the
+// torrent library reverifies it against the piece hashes as it's accessed, so
+// there's no separate completed-pieces bitfield to persist here.
+func (c *Client) SaveSession() error {
+	dir := c.sessionDir()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("session: couldn't create session directory: %v", err)
+	}
+
+	torrentsDir := path.Join(dir, "torrents")
+	if err := os.MkdirAll(torrentsDir, 0o755); err != nil {
+		return fmt.Errorf("session: couldn't create cached torrents directory: %v", err)
+	}
+
+	manifest := sessionManifest{}
+	for _, t := range c.TorrentClient.Torrents() {
+		<-t.GotInfo()
+
+		cached := path.Join(torrentsDir, t.InfoHash().String()+".torrent")
+		cachedFile, err := os.Create(cached)
+		if err != nil {
+			return fmt.Errorf("session: couldn't create cached torrent file for %v: %v", t.InfoHash(), err)
+		}
+		mi := t.Metainfo()
+		err = mi.Write(cachedFile)
+		cachedFile.Close()
+		if err != nil {
+			return fmt.Errorf("session: couldn't cache torrent file for %v: %v", t.InfoHash(), err)
+		}
+
+		manifest.Torrents = append(manifest.Torrents, sessionEntry{
+			Source:       cached,
+			Seed:         c.Seed,
+			SelectedFile: c.selectedFile(t.InfoHash().String()),
+		})
+	}
+
+	f, err := os.Create(c.manifestPath())
+	if err != nil {
+		return fmt.Errorf("session: couldn't create manifest: %v", err)
+	}
+	defer f.Close()
+
+	return json.NewEncoder(f).Encode(manifest)
+}
+
+// RestoreSession reloads the manifest written by SaveSession, if any, and re-adds
+// every torrent it describes. It's a no-op, not an error, when no manifest exists
+// yet (e.g. the first run of a long-lived daemon).
+func (c *Client) RestoreSession() error {
+	data, err := os.ReadFile(c.manifestPath())
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("session: couldn't read manifest: %v", err)
+	}
+
+	var manifest sessionManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("session: couldn't parse manifest: %v", err)
+	}
+
+	for _, entry := range manifest.Torrents {
+		t, err := c.AddTorrent(entry.Source)
+		if err != nil {
+			return fmt.Errorf("session: couldn't restore torrent %v: %v", entry.Source, err)
+		}
+		t.DownloadAll()
+
+		if entry.SelectedFile > 0 {
+			if f, err := GetVideoFile(t, entry.SelectedFile); err == nil {
+				f.SetPriority(torrent.PiecePriorityReadahead)
+			}
+		}
+	}
+
+	return nil
+}
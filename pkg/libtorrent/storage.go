@@ -0,0 +1,211 @@
+package libtorrent
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/anacrolix/torrent/metainfo"
+	"github.com/anacrolix/torrent/storage"
+)
+
+// StorageBackend selects the on-disk (or in-memory) representation used to hold
+// downloaded piece data.
+type StorageBackend int
+
+const (
+	// StorageFile namespaces torrent data under DataDir/<info hash>, same as before.
+	StorageFile StorageBackend = iota
+	// StorageMMap memory-maps torrent files, avoiding userspace copies on reads,
+	// which matters for the streaming path's http.ServeContent.
+	StorageMMap
+	// StoragePieceFile stores all pieces of a torrent in a single file keyed by
+	// piece index, rather than reconstructing the original file layout on disk.
+	StoragePieceFile
+	// StorageMemory keeps piece data in RAM only, capped by CacheBytes, enabling
+	// ephemeral "watch and discard" playback with nothing written to disk.
+	StorageMemory
+)
+
+// getStorageImpl resolves the storage.ClientImplCloser used for cfg.DefaultStorage,
+// honouring StorageOpener as an escape hatch before falling back to c.Backend.
+func (c *Client) getStorageImpl() (storage.ClientImplCloser, error) {
+	if c.StorageOpener != nil {
+		return storageOpenerAdapter{c.StorageOpener}, nil
+	}
+
+	switch c.Backend {
+	case StorageFile:
+		return storage.NewFileByInfoHash(c.DataDir), nil
+	case StorageMMap:
+		return storage.NewMMap(c.DataDir), nil
+	case StoragePieceFile:
+		return storage.NewBoltDB(c.DataDir), nil
+	case StorageMemory:
+		return newMemoryStorage(c.CacheBytes), nil
+	default:
+		return nil, fmt.Errorf("client: unknown storage backend: %v", c.Backend)
+	}
+}
+
+// storageOpenerAdapter lets a StorageOpener func stand in for a
+// storage.ClientImplCloser; opened torrents are tracked so Close can close them all.
+type storageOpenerAdapter struct {
+	open func(info *metainfo.Info, infoHash metainfo.Hash) storage.ClientImplCloser
+}
+
+func (a storageOpenerAdapter) OpenTorrent(ctx context.Context, info *metainfo.Info, infoHash metainfo.Hash) (storage.TorrentImpl, error) {
+	return a.open(info, infoHash).OpenTorrent(ctx, info, infoHash)
+}
+
+func (a storageOpenerAdapter) Close() error {
+	return nil
+}
+
+// memoryStorage is an in-memory, ring-buffer-like storage.ClientImpl: completed
+// pieces are evicted once the cumulative cached size crosses cacheBytes, oldest
+// first, so long-running streams don't grow without bound.
+type memoryStorage struct {
+	mu         sync.Mutex
+	cacheBytes int64
+	used       int64
+	order      []pieceKey
+	pieces     map[pieceKey]*memoryPiece
+}
+
+type pieceKey struct {
+	infoHash metainfo.Hash
+	index    int
+}
+
+type memoryPiece struct {
+	data     []byte
+	complete bool
+}
+
+func newMemoryStorage(cacheBytes int64) *memoryStorage {
+	return &memoryStorage{
+		cacheBytes: cacheBytes,
+		pieces:     make(map[pieceKey]*memoryPiece),
+	}
+}
+
+func (m *memoryStorage) OpenTorrent(ctx context.Context, info *metainfo.Info, infoHash metainfo.Hash) (storage.TorrentImpl, error) {
+	return storage.TorrentImpl{
+		Piece: func(p metainfo.Piece) storage.PieceImpl {
+			return &memoryPieceImpl{
+				m:    m,
+				key:  pieceKey{infoHash: infoHash, index: p.Index()},
+				size: p.Length(),
+			}
+		},
+		Close: func() error { return nil },
+	}, nil
+}
+
+func (m *memoryStorage) Close() error {
+	return nil
+}
+
+// ensureLocked returns the piece for key, allocating it (and reserving its size
+// against cacheBytes) if this is the first time it's seen. Must be called with
+// m.mu held.
+func (m *memoryStorage) ensureLocked(key pieceKey, size int64) *memoryPiece {
+	if p, ok := m.pieces[key]; ok {
+		return p
+	}
+
+	p := &memoryPiece{data: make([]byte, size)}
+	m.pieces[key] = p
+	m.order = append(m.order, key)
+	m.used += size
+	m.evictLocked()
+	return p
+}
+
+// evictLocked drops completed pieces, oldest first, until usage fits
+// cacheBytes. Pieces that aren't complete yet are skipped in place rather than
+// stopping eviction entirely, so one slow piece can't jam eviction for every
+// piece behind it. Must be called with m.mu held.
+func (m *memoryStorage) evictLocked() {
+	if m.cacheBytes <= 0 {
+		return
+	}
+
+	kept := m.order[:0]
+	for _, key := range m.order {
+		if m.used <= m.cacheBytes {
+			kept = append(kept, key)
+			continue
+		}
+
+		p, ok := m.pieces[key]
+		if !ok {
+			continue
+		}
+		if !p.complete {
+			kept = append(kept, key)
+			continue
+		}
+
+		delete(m.pieces, key)
+		m.used -= int64(len(p.data))
+	}
+	m.order = kept
+}
+
+type memoryPieceImpl struct {
+	m    *memoryStorage
+	key  pieceKey
+	size int64
+}
+
+func (p *memoryPieceImpl) ReadAt(b []byte, off int64) (int, error) {
+	p.m.mu.Lock()
+	defer p.m.mu.Unlock()
+
+	piece, ok := p.m.pieces[p.key]
+	if !ok {
+		return 0, fmt.Errorf("memory storage: piece not written: %v", p.key.index)
+	}
+	return copy(b, piece.data[off:]), nil
+}
+
+func (p *memoryPieceImpl) WriteAt(b []byte, off int64) (int, error) {
+	p.m.mu.Lock()
+	defer p.m.mu.Unlock()
+
+	piece := p.m.ensureLocked(p.key, p.size)
+	return copy(piece.data[off:], b), nil
+}
+
+func (p *memoryPieceImpl) MarkComplete() error {
+	p.m.mu.Lock()
+	defer p.m.mu.Unlock()
+
+	piece := p.m.ensureLocked(p.key, p.size)
+	piece.complete = true
+	p.m.evictLocked()
+	return nil
+}
+
+func (p *memoryPieceImpl) MarkNotComplete() error {
+	p.m.mu.Lock()
+	defer p.m.mu.Unlock()
+
+	if piece, ok := p.m.pieces[p.key]; ok {
+		piece.complete = false
+	}
+	return nil
+}
+
+func (p *memoryPieceImpl) Completion() storage.Completion {
+	p.m.mu.Lock()
+	defer p.m.mu.Unlock()
+
+	piece, ok := p.m.pieces[p.key]
+	if !ok {
+		return storage.Completion{Complete: false, Ok: true}
+	}
+	return storage.Completion{Complete: piece.complete, Ok: true}
+}
@@ -0,0 +1,75 @@
+package libtorrent
+
+import "testing"
+
+func markComplete(m *memoryStorage, key pieceKey) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pieces[key].complete = true
+}
+
+func TestEvictLockedSkipsIncompletePieces(t *testing.T) {
+	m := newMemoryStorage(30)
+
+	keys := []pieceKey{{index: 0}, {index: 1}, {index: 2}, {index: 3}}
+	for _, k := range keys {
+		m.mu.Lock()
+		m.ensureLocked(k, 10)
+		m.mu.Unlock()
+	}
+	// Piece 0 (the oldest) is never marked complete; 1, 2 and 3 are.
+	markComplete(m, keys[1])
+	markComplete(m, keys[2])
+	markComplete(m, keys[3])
+
+	m.mu.Lock()
+	m.evictLocked()
+	m.mu.Unlock()
+
+	// used was 40 against a cap of 30: eviction must skip the stalled
+	// piece 0 and evict complete piece 1 instead, rather than stopping at
+	// the first incomplete entry in order.
+	if _, ok := m.pieces[keys[0]]; !ok {
+		t.Error("incomplete piece 0 was evicted, want it kept")
+	}
+	if _, ok := m.pieces[keys[1]]; ok {
+		t.Error("complete piece 1 was kept, want it evicted to free space")
+	}
+	if m.used > m.cacheBytes {
+		t.Errorf("used = %d, want <= cacheBytes (%d)", m.used, m.cacheBytes)
+	}
+}
+
+func TestEvictLockedNoopUnderCap(t *testing.T) {
+	m := newMemoryStorage(100)
+	key := pieceKey{index: 0}
+	m.mu.Lock()
+	m.ensureLocked(key, 10)
+	m.mu.Unlock()
+	markComplete(m, key)
+
+	m.mu.Lock()
+	m.evictLocked()
+	m.mu.Unlock()
+
+	if _, ok := m.pieces[key]; !ok {
+		t.Error("piece was evicted while usage was under cacheBytes")
+	}
+}
+
+func TestEnsureLockedReusesExistingPiece(t *testing.T) {
+	m := newMemoryStorage(0)
+	key := pieceKey{index: 0}
+
+	m.mu.Lock()
+	p1 := m.ensureLocked(key, 10)
+	p2 := m.ensureLocked(key, 10)
+	m.mu.Unlock()
+
+	if p1 != p2 {
+		t.Error("ensureLocked allocated a second piece for an existing key")
+	}
+	if m.used != 10 {
+		t.Errorf("used = %d, want 10 (allocated once)", m.used)
+	}
+}
@@ -0,0 +1,193 @@
+package libtorrent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anacrolix/torrent"
+)
+
+// ctxReadSeeker wraps a torrent.Reader so that a blocked Read (waiting on pieces
+// that never arrive) is unblocked once the owning request's context is done,
+// instead of leaking the connection until the swarm catches up.
+type ctxReadSeeker struct {
+	ctx context.Context
+	r   torrent.Reader
+}
+
+func (c *ctxReadSeeker) Read(p []byte) (int, error) {
+	select {
+	case <-c.ctx.Done():
+		return 0, c.ctx.Err()
+	default:
+	}
+	return c.r.Read(p)
+}
+
+func (c *ctxReadSeeker) Seek(offset int64, whence int) (int64, error) {
+	return c.r.Seek(offset, whence)
+}
+
+// findTorrent looks up a loaded torrent by its info hash, waiting for metadata if
+// necessary.
+func (c *Client) findTorrent(hash string) (*torrent.Torrent, error) {
+	for _, t := range c.TorrentClient.Torrents() {
+		if t.InfoHash().String() != hash {
+			continue
+		}
+		<-t.GotInfo()
+		return t, nil
+	}
+	return nil, fmt.Errorf("stream handler: no torrent matches hash: %v", hash)
+}
+
+// resolveFile picks a file out of a torrent either by its ?path= (URL-encoded,
+// relative to the torrent root) or by its 1-based ?ep= sorted index.
+func (c *Client) resolveFile(t *torrent.Torrent, queries url.Values) (*torrent.File, error) {
+	if p := queries.Get("path"); p != "" {
+		decoded, err := url.QueryUnescape(p)
+		if err != nil {
+			return nil, fmt.Errorf("stream handler: invalid path query: %v", err)
+		}
+		for _, f := range t.Files() {
+			if f.Path() == decoded {
+				return f, nil
+			}
+		}
+		return nil, fmt.Errorf("stream handler: no file at path: %v", decoded)
+	}
+
+	ep, err := strconv.Atoi(queries.Get("ep"))
+	if err != nil {
+		return nil, errors.New("stream handler: missing or invalid ep/path query")
+	}
+
+	f, err := GetVideoFile(t, ep)
+	if err != nil {
+		return nil, err
+	}
+	c.trackSelectedFile(t.InfoHash().String(), ep)
+	return f, nil
+}
+
+// contentType detects the MIME type of f, first by extension and, failing that, by
+// sniffing the first 512 bytes of the file via the torrent reader.
+func contentType(f *torrent.File) string {
+	if t := mime.TypeByExtension(path.Ext(f.Path())); t != "" {
+		return t
+	}
+
+	r := f.NewReader()
+	defer r.Close()
+
+	buf := make([]byte, 512)
+	n, _ := io.ReadFull(r, buf)
+	if n == 0 {
+		return "application/octet-stream"
+	}
+	return http.DetectContentType(buf[:n])
+}
+
+// StreamHandler serves a single file out of a torrent with correct Content-Type,
+// range and HEAD support. It supersedes the old fixed "video/mp4" handler so mkv/webm
+// playback and player HEAD probes work in the browser.
+func (c *Client) StreamHandler(w http.ResponseWriter, r *http.Request) {
+	queries := r.URL.Query()
+	hash := strings.TrimSpace(queries.Get("hash"))
+	if hash == "" {
+		http.Error(w, "stream handler: hash query is empty", http.StatusBadRequest)
+		return
+	}
+
+	t, err := c.findTorrent(hash)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	f, err := c.resolveFile(t, queries)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType(f))
+	w.Header().Set("Accept-Ranges", "bytes")
+
+	if r.Method == http.MethodHead {
+		w.Header().Set("Content-Length", strconv.FormatInt(f.Length(), 10))
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	f.SetPriority(torrent.PiecePriorityReadahead)
+	c.boostHeadTail(t, f, torrent.PiecePriorityNow)
+
+	reader := f.NewReader()
+	if c.ReadaheadBytes > 0 {
+		reader.SetReadahead(c.ReadaheadBytes)
+	}
+	defer reader.Close()
+
+	go func() {
+		<-r.Context().Done()
+		f.SetPriority(torrent.PiecePriorityNone)
+		c.boostHeadTail(t, f, torrent.PiecePriorityNone)
+	}()
+
+	http.ServeContent(w, r, f.DisplayPath(), time.Unix(t.Metainfo().CreationDate, 0), &ctxReadSeeker{ctx: r.Context(), r: reader})
+}
+
+// FileListing describes one file inside a torrent, as returned by /files.
+type FileListing struct {
+	Path   string `json:"path"`
+	Ep     int    `json:"ep"`
+	Length int64  `json:"length"`
+	// Tracks lists the paths of subtitle sidecars discovered for this file via
+	// GetMediaBundle, if any.
+	Tracks []string `json:"tracks,omitempty"`
+}
+
+// FilesHandler lists the files of a torrent as JSON so front-ends can enumerate
+// episodes instead of guessing sorted indices.
+func (c *Client) FilesHandler(w http.ResponseWriter, r *http.Request) {
+	hash := strings.TrimSpace(r.URL.Query().Get("hash"))
+	if hash == "" {
+		http.Error(w, "files handler: hash query is empty", http.StatusBadRequest)
+		return
+	}
+
+	t, err := c.findTorrent(hash)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	files := getSortedFilesList(t)
+	listing := make([]FileListing, len(files))
+	for i, f := range files {
+		entry := FileListing{Path: f.Path(), Ep: i + 1, Length: f.Length()}
+		if bundle, err := GetMediaBundle(t, i+1); err == nil {
+			for _, sub := range bundle.Subtitles {
+				entry.Tracks = append(entry.Tracks, sub.Path())
+			}
+		}
+		listing[i] = entry
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(listing); err != nil {
+		log.Println(err)
+	}
+}
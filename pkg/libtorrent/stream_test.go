@@ -0,0 +1,71 @@
+package libtorrent
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestResolveFilePathTakesPrecedenceOverEp(t *testing.T) {
+	c := &Client{}
+	tt := newTestTorrent(t, "show", map[string]int64{
+		"Show/episode01.mkv": 1000,
+		"Show/episode02.mkv": 1000,
+	})
+
+	q := url.Values{}
+	q.Set("path", url.QueryEscape("show/Show/episode02.mkv"))
+	q.Set("ep", "1")
+
+	f, err := c.resolveFile(tt, q)
+	if err != nil {
+		t.Fatalf("resolveFile: %v", err)
+	}
+	if !strings.HasSuffix(f.Path(), "episode02.mkv") {
+		t.Errorf("got %q, want path query (episode02.mkv) to win over ep query", f.Path())
+	}
+}
+
+func TestResolveFileFallsBackToEp(t *testing.T) {
+	c := &Client{}
+	tt := newTestTorrent(t, "show", map[string]int64{
+		"Show/episode01.mkv": 1000,
+		"Show/episode02.mkv": 1000,
+	})
+
+	q := url.Values{}
+	q.Set("ep", "2")
+
+	f, err := c.resolveFile(tt, q)
+	if err != nil {
+		t.Fatalf("resolveFile: %v", err)
+	}
+	if !strings.HasSuffix(f.Path(), "episode02.mkv") {
+		t.Errorf("got %q, want episode02.mkv", f.Path())
+	}
+}
+
+func TestResolveFileUnknownPath(t *testing.T) {
+	c := &Client{}
+	tt := newTestTorrent(t, "show", map[string]int64{
+		"Show/episode01.mkv": 1000,
+	})
+
+	q := url.Values{}
+	q.Set("path", url.QueryEscape("nope.mkv"))
+
+	if _, err := c.resolveFile(tt, q); err == nil {
+		t.Error("resolveFile: want error for nonexistent path, got nil")
+	}
+}
+
+func TestResolveFileMissingQueries(t *testing.T) {
+	c := &Client{}
+	tt := newTestTorrent(t, "show", map[string]int64{
+		"Show/episode01.mkv": 1000,
+	})
+
+	if _, err := c.resolveFile(tt, url.Values{}); err == nil {
+		t.Error("resolveFile: want error when neither path nor ep is set, got nil")
+	}
+}
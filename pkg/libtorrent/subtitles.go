@@ -0,0 +1,147 @@
+package libtorrent
+
+import (
+	"bufio"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/anacrolix/torrent"
+)
+
+var subtitleExts = []string{".srt", ".ass", ".ssa", ".vtt"}
+
+// MediaBundle groups a chosen video file with any subtitle sidecars discovered
+// alongside it in the same torrent.
+type MediaBundle struct {
+	Video     *torrent.File
+	Subtitles []*torrent.File
+}
+
+// GetMediaBundle returns the chosen episode's video file plus any subtitle files
+// discovered by longest-common-prefix matching of filenames against it, within
+// the same directory in the torrent.
+func GetMediaBundle(t *torrent.Torrent, episode int) (*MediaBundle, error) {
+	video, err := GetVideoFile(t, episode)
+	if err != nil {
+		return nil, err
+	}
+
+	videoDir := path.Dir(video.Path())
+	videoStem := strings.TrimSuffix(path.Base(video.Path()), path.Ext(video.Path()))
+
+	var subs []*torrent.File
+	bestLen := -1
+	for _, f := range t.Files() {
+		if f == video || path.Dir(f.Path()) != videoDir {
+			continue
+		}
+		ext := path.Ext(f.Path())
+		if !slices.Contains(subtitleExts, ext) {
+			continue
+		}
+
+		stem := strings.TrimSuffix(path.Base(f.Path()), ext)
+		n := commonPrefixLen(videoStem, stem)
+		switch {
+		case n > bestLen:
+			bestLen = n
+			subs = []*torrent.File{f}
+		case n == bestLen:
+			subs = append(subs, f)
+		}
+	}
+
+	return &MediaBundle{Video: video, Subtitles: subs}, nil
+}
+
+func commonPrefixLen(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+// SubsHandler streams a subtitle sidecar of an episode, picked via GetMediaBundle.
+// When ?format=vtt is requested and the source is a .srt file, it's converted to
+// WebVTT on the fly so HTML5 <track> elements can consume it directly.
+func (c *Client) SubsHandler(w http.ResponseWriter, r *http.Request) {
+	queries := r.URL.Query()
+	hash := strings.TrimSpace(queries.Get("hash"))
+	if hash == "" {
+		http.Error(w, "subs handler: hash query is empty", http.StatusBadRequest)
+		return
+	}
+
+	t, err := c.findTorrent(hash)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	ep, err := strconv.Atoi(queries.Get("ep"))
+	if err != nil {
+		http.Error(w, "subs handler: missing or invalid ep query", http.StatusBadRequest)
+		return
+	}
+
+	bundle, err := GetMediaBundle(t, ep)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	track := 0
+	if tq := queries.Get("track"); tq != "" {
+		if track, err = strconv.Atoi(tq); err != nil {
+			http.Error(w, "subs handler: invalid track query", http.StatusBadRequest)
+			return
+		}
+	}
+	if track < 0 || track >= len(bundle.Subtitles) {
+		http.Error(w, "subs handler: no subtitle at that track index", http.StatusNotFound)
+		return
+	}
+
+	sub := bundle.Subtitles[track]
+	reader := sub.NewReader()
+	defer reader.Close()
+
+	if queries.Get("format") == "vtt" && path.Ext(sub.Path()) == ".srt" {
+		w.Header().Set("Content-Type", "text/vtt")
+		if err := srtToVTT(reader, w); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType(sub))
+	if _, err := io.Copy(w, reader); err != nil {
+		log.Println(err)
+	}
+}
+
+var srtTimestamp = regexp.MustCompile(`(\d{2}:\d{2}:\d{2}),(\d{3})`)
+
+// srtToVTT writes r, an SRT subtitle stream, to w as WebVTT: a "WEBVTT" header
+// followed by the same cues with ',' millisecond separators swapped for '.'.
+func srtToVTT(r io.Reader, w io.Writer) error {
+	if _, err := io.WriteString(w, "WEBVTT\n\n"); err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := srtTimestamp.ReplaceAllString(scanner.Text(), "$1.$2")
+		if _, err := io.WriteString(w, line+"\n"); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,128 @@
+package libtorrent
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anacrolix/torrent"
+)
+
+func TestCommonPrefixLen(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want int
+	}{
+		{"identical", "episode01", "episode01", 9},
+		{"no overlap", "abc", "xyz", 0},
+		{"partial overlap", "episode01", "episode02", 8},
+		{"one empty", "episode01", "", 0},
+		{"both empty", "", "", 0},
+		{"b prefix of a", "episode01", "episode0", 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := commonPrefixLen(tt.a, tt.b); got != tt.want {
+				t.Errorf("commonPrefixLen(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+// episodeOf returns the 1-based index newTestTorrent's sibling
+// newTestTorrent assigns videoPath under GetVideoFile's global alphabetical
+// ordering, so tests can locate their target video without hardcoding a sort
+// position that shifts whenever the fixture's other filenames change.
+func episodeOf(t *testing.T, tt *torrent.Torrent, videoPath string) int {
+	t.Helper()
+	for i, f := range getSortedFilesList(tt) {
+		if strings.HasSuffix(f.Path(), videoPath) {
+			return i + 1
+		}
+	}
+	t.Fatalf("no file ending in %q in torrent", videoPath)
+	return 0
+}
+
+func TestGetMediaBundle(t *testing.T) {
+	tt := newTestTorrent(t, "show", map[string]int64{
+		"Show/ep01.mkv":   1000,
+		"Show/ep01.srt":   100,
+		"Show/ep01.ssa":   100,
+		"Show/ep02.srt":   100,
+		"Show/zzz.vtt":    100,
+		"Show/readme.txt": 10,
+	})
+
+	bundle, err := GetMediaBundle(tt, episodeOf(t, tt, "ep01.mkv"))
+	if err != nil {
+		t.Fatalf("GetMediaBundle: %v", err)
+	}
+	if !strings.HasSuffix(bundle.Video.Path(), "ep01.mkv") {
+		t.Fatalf("video = %q, want ep01.mkv", bundle.Video.Path())
+	}
+
+	// ep01.srt and ep01.ssa share the longest common prefix ("ep01") with
+	// the video's stem, so both are picked and the shorter-prefix
+	// zzz.vtt/ep02.srt are excluded.
+	if len(bundle.Subtitles) != 2 {
+		t.Fatalf("got %d subtitles, want 2: %v", len(bundle.Subtitles), bundle.Subtitles)
+	}
+	for _, s := range bundle.Subtitles {
+		if !strings.Contains(s.Path(), "ep01.") {
+			t.Errorf("unexpected subtitle in tie-break result: %q", s.Path())
+		}
+	}
+}
+
+func TestGetMediaBundleIgnoresOtherDirectories(t *testing.T) {
+	tt := newTestTorrent(t, "show", map[string]int64{
+		"Vid/ep01.mkv": 1000,
+		"Zub/ep01.srt": 100,
+	})
+
+	bundle, err := GetMediaBundle(tt, episodeOf(t, tt, "ep01.mkv"))
+	if err != nil {
+		t.Fatalf("GetMediaBundle: %v", err)
+	}
+	if len(bundle.Subtitles) != 0 {
+		t.Fatalf("got %d subtitles from a different directory, want 0", len(bundle.Subtitles))
+	}
+}
+
+func TestSrtToVTT(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "single cue",
+			input: "1\n00:00:01,000 --> 00:00:02,500\nHello\n",
+			want:  "WEBVTT\n\n1\n00:00:01.000 --> 00:00:02.500\nHello\n",
+		},
+		{
+			name:  "multiple timestamps per line",
+			input: "00:00:01,000 --> 00:00:02,000\n",
+			want:  "WEBVTT\n\n00:00:01.000 --> 00:00:02.000\n",
+		},
+		{
+			name:  "no timestamps",
+			input: "just plain text\n",
+			want:  "WEBVTT\n\njust plain text\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			if err := srtToVTT(strings.NewReader(tt.input), &out); err != nil {
+				t.Fatalf("srtToVTT: %v", err)
+			}
+			if got := out.String(); got != tt.want {
+				t.Errorf("srtToVTT(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}